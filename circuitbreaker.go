@@ -0,0 +1,124 @@
+package fallback
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitBreaker represents a pluggable guard consulted by
+// ExecuteHTTPRequestContext before dialing a Connection. Allow reports
+// whether a request should be attempted; Success and Failure report the
+// outcome of an attempt that Allow permitted, so the breaker can track
+// upstream health. Implementations must be safe for concurrent use, since a
+// hedged Connection may invoke them from more than one goroutine.
+type CircuitBreaker interface {
+	Allow() bool
+	Success()
+	Failure()
+}
+
+// breakerState is the internal state machine backing DefaultCircuitBreaker.
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// DefaultCircuitBreaker is the CircuitBreaker implementation shipped with
+// this package. It opens after FailureThreshold consecutive failures, stays
+// open for Cooldown, and then allows up to HalfOpenProbes concurrent probe
+// requests through before deciding whether to close again or re-open.
+type DefaultCircuitBreaker struct {
+	FailureThreshold int
+	Cooldown         time.Duration
+	HalfOpenProbes   int
+
+	mu          sync.Mutex
+	state       breakerState
+	failures    int
+	openedAt    time.Time
+	probesInUse int
+}
+
+// NewDefaultCircuitBreaker returns a DefaultCircuitBreaker configured with
+// the given failure threshold, cooldown, and half-open probe count. A
+// halfOpenProbes of zero or less is treated as one.
+func NewDefaultCircuitBreaker(failureThreshold int, cooldown time.Duration,
+	halfOpenProbes int) *DefaultCircuitBreaker {
+
+	if halfOpenProbes <= 0 {
+		halfOpenProbes = 1
+	}
+
+	return &DefaultCircuitBreaker{
+		FailureThreshold: failureThreshold,
+		Cooldown:         cooldown,
+		HalfOpenProbes:   halfOpenProbes,
+	}
+}
+
+// Allow reports whether a request should be attempted, transitioning an open
+// breaker to half-open once Cooldown has elapsed.
+func (breaker *DefaultCircuitBreaker) Allow() bool {
+
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	switch breaker.state {
+	case breakerClosed:
+		return true
+
+	case breakerOpen:
+		if time.Since(breaker.openedAt) < breaker.Cooldown {
+			return false
+		}
+		breaker.state = breakerHalfOpen
+		breaker.probesInUse = 0
+		fallthrough
+
+	case breakerHalfOpen:
+		if breaker.probesInUse >= breaker.HalfOpenProbes {
+			return false
+		}
+		breaker.probesInUse++
+		return true
+	}
+
+	return true
+}
+
+// Success records a successful attempt, closing the breaker if it was
+// half-open.
+func (breaker *DefaultCircuitBreaker) Success() {
+
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	breaker.failures = 0
+	breaker.probesInUse = 0
+	breaker.state = breakerClosed
+}
+
+// Failure records a failed attempt, opening the breaker once FailureThreshold
+// consecutive failures have been observed, or immediately re-opening a
+// half-open breaker whose probe failed.
+func (breaker *DefaultCircuitBreaker) Failure() {
+
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	if breaker.state == breakerHalfOpen {
+		breaker.state = breakerOpen
+		breaker.openedAt = time.Now()
+		breaker.probesInUse = 0
+		return
+	}
+
+	breaker.failures++
+	if breaker.failures >= breaker.FailureThreshold {
+		breaker.state = breakerOpen
+		breaker.openedAt = time.Now()
+	}
+}
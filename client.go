@@ -0,0 +1,27 @@
+package fallback
+
+import (
+	"net/http"
+	"time"
+)
+
+// DefaultClientTimeout bounds DefaultClient's requests so a stuck connection
+// cannot hang a Connection forever when neither Connection.Timeout nor the
+// caller's context carries a deadline.
+const DefaultClientTimeout = 30 * time.Second
+
+// DefaultClient is the *http.Client used by a Connection whose Client field
+// is nil. It is shared across every such Connection so that keep-alive pools
+// are reused across fallback hops rather than rebuilt on every call.
+var DefaultClient = &http.Client{Timeout: DefaultClientTimeout}
+
+// httpClient returns the *http.Client this Connection dials through:
+// Connection.Client when set, otherwise DefaultClient.
+func (connection Connection) httpClient() *http.Client {
+
+	if connection.Client != nil {
+		return connection.Client
+	}
+
+	return DefaultClient
+}
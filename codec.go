@@ -0,0 +1,88 @@
+package fallback
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"mime"
+	"sync"
+)
+
+// Codec marshals and unmarshals the Body, Output, and CustomError payloads
+// exchanged with a Connection, decoupling the package from any single wire
+// format. ContentType identifies the media type this Codec produces and
+// consumes, and is used as its key in the package-level codec registry.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+	ContentType() string
+}
+
+// jsonCodec is the Codec used when no Content-Type is recognised and no
+// Connection.Codec override is set, preserving this package's original
+// behaviour.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (jsonCodec) ContentType() string                        { return "application/json" }
+
+// xmlCodec is the built-in Codec for "application/xml" and "text/xml".
+type xmlCodec struct{}
+
+func (xmlCodec) Marshal(v interface{}) ([]byte, error)      { return xml.Marshal(v) }
+func (xmlCodec) Unmarshal(data []byte, v interface{}) error { return xml.Unmarshal(data, v) }
+func (xmlCodec) ContentType() string                        { return "application/xml" }
+
+var (
+	codecRegistryMu sync.RWMutex
+	codecRegistry   = map[string]Codec{
+		"application/json": jsonCodec{},
+		"application/xml":  xmlCodec{},
+		"text/xml":         xmlCodec{},
+	}
+)
+
+// RegisterCodec adds codec to the package-level registry, keyed by
+// codec.ContentType(), overriding any existing Codec registered under the
+// same media type. Use this to plug in protobuf, MessagePack, or any other
+// wire format.
+func RegisterCodec(codec Codec) {
+
+	codecRegistryMu.Lock()
+	defer codecRegistryMu.Unlock()
+
+	codecRegistry[codec.ContentType()] = codec
+}
+
+// CodecForContentType returns the Codec registered for the media type in
+// contentType (ignoring any parameters, such as charset), and reports
+// whether one was found.
+func CodecForContentType(contentType string) (Codec, bool) {
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+
+	codecRegistryMu.RLock()
+	defer codecRegistryMu.RUnlock()
+
+	codec, ok := codecRegistry[mediaType]
+	return codec, ok
+}
+
+// resolveCodec selects the Codec that applies to contentType: Connection.Codec
+// takes precedence when set, then the registry lookup, falling back to JSON
+// to preserve this package's original behaviour.
+func (connection Connection) resolveCodec(contentType string) Codec {
+
+	if connection.Codec != nil {
+		return connection.Codec
+	}
+
+	if codec, ok := CodecForContentType(contentType); ok {
+		return codec
+	}
+
+	return jsonCodec{}
+}
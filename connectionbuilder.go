@@ -1,7 +1,8 @@
 package fallback
 
 import (
-	"encoding/json"
+	"net/http"
+	"time"
 )
 
 type connectionBuilder interface {
@@ -10,6 +11,7 @@ type connectionBuilder interface {
 	addHTTPHeaders()
 	addPayloads()
 	addFallback()
+	addLogger()
 }
 
 // ConnectionBuilder represents a Builder-pattern based means of constructing
@@ -20,6 +22,21 @@ type ConnectionBuilder struct {
 	body, output, customError interface{}
 	headers                   map[string]string
 	fallback                  Connecter
+	logger                    Logger
+
+	retryMax                   int
+	retryWaitMin, retryWaitMax time.Duration
+	checkRetry                 CheckRetryFunc
+
+	timeout, hedge time.Duration
+
+	breaker CircuitBreaker
+
+	codec Codec
+
+	client *http.Client
+
+	middleware []Middleware
 
 	Connection *Connection
 }
@@ -28,7 +45,7 @@ type ConnectionBuilder struct {
 // specified metadata pertaining to ConnectionBuilder.
 func NewConnectionBuilder(name, method, path string, returnsJSON bool,
 	body interface{}, headers map[string]string, output,
-	customError interface{}, fallback Connecter) *ConnectionBuilder {
+	customError interface{}, fallback Connecter, logger Logger) *ConnectionBuilder {
 
 	return &ConnectionBuilder{
 		name:        name,
@@ -36,13 +53,83 @@ func NewConnectionBuilder(name, method, path string, returnsJSON bool,
 		path:        path,
 		returnsJSON: returnsJSON,
 		body:        body,
+		headers:     headers,
 		output:      output,
 		customError: customError,
-		headers:     headers,
 		fallback:    fallback,
+		logger:      logger,
 	}
 }
 
+// WithRetry configures the retry policy applied by the built Connection
+// before it falls back: max retries, the exponential backoff bounds, and an
+// optional CheckRetryFunc override. It returns builder to allow call
+// chaining.
+func (builder *ConnectionBuilder) WithRetry(retryMax int,
+	retryWaitMin, retryWaitMax time.Duration,
+	checkRetry CheckRetryFunc) *ConnectionBuilder {
+
+	builder.retryMax = retryMax
+	builder.retryWaitMin = retryWaitMin
+	builder.retryWaitMax = retryWaitMax
+	builder.checkRetry = checkRetry
+
+	return builder
+}
+
+// WithTimeout bounds the built Connection's execution with a per-Connection
+// timeout, applied via context.WithTimeout around
+// ExecuteHTTPRequestContext. It returns builder to allow call chaining.
+func (builder *ConnectionBuilder) WithTimeout(timeout time.Duration) *ConnectionBuilder {
+
+	builder.timeout = timeout
+	return builder
+}
+
+// WithHedge races the built Connection's Fallback against it once hedge has
+// elapsed without a result, taking whichever succeeds first. It has no
+// effect unless a Fallback is also configured. It returns builder to allow
+// call chaining.
+func (builder *ConnectionBuilder) WithHedge(hedge time.Duration) *ConnectionBuilder {
+
+	builder.hedge = hedge
+	return builder
+}
+
+// WithCircuitBreaker configures the CircuitBreaker consulted before dialing
+// the built Connection. It returns builder to allow call chaining.
+func (builder *ConnectionBuilder) WithCircuitBreaker(breaker CircuitBreaker) *ConnectionBuilder {
+
+	builder.breaker = breaker
+	return builder
+}
+
+// WithCodec overrides the Codec used to marshal the request Body and
+// unmarshal Output/CustomError, in place of the Content-Type-driven registry
+// lookup. It returns builder to allow call chaining.
+func (builder *ConnectionBuilder) WithCodec(codec Codec) *ConnectionBuilder {
+
+	builder.codec = codec
+	return builder
+}
+
+// WithClient overrides the *http.Client used to dial the built Connection, in
+// place of DefaultClient. It returns builder to allow call chaining.
+func (builder *ConnectionBuilder) WithClient(client *http.Client) *ConnectionBuilder {
+
+	builder.client = client
+	return builder
+}
+
+// WithMiddleware appends middleware to the chain wrapped around the built
+// Connection's round trips, in the order given. It returns builder to allow
+// call chaining.
+func (builder *ConnectionBuilder) WithMiddleware(middleware ...Middleware) *ConnectionBuilder {
+
+	builder.middleware = append(builder.middleware, middleware...)
+	return builder
+}
+
 func (builder *ConnectionBuilder) createConnection() {
 
 	builder.Connection = &Connection{
@@ -52,15 +139,25 @@ func (builder *ConnectionBuilder) createConnection() {
 	}
 
 	if builder.returnsJSON {
+		codec := builder.codec
+		if codec == nil {
+			codec = jsonCodec{}
+		}
+
 		builder.Connection.Headers = map[string]string{
-			"Content-Type": "application/json",
+			"Content-Type": codec.ContentType(),
 		}
 	}
 }
 
 func (builder *ConnectionBuilder) addHTTPPOSTBody() error {
 
-	body, err := json.Marshal(builder.body)
+	codec := builder.codec
+	if codec == nil {
+		codec = jsonCodec{}
+	}
+
+	body, err := codec.Marshal(builder.body)
 	if err != nil {
 		return err
 	}
@@ -91,6 +188,49 @@ func (builder *ConnectionBuilder) addFallback() {
 	builder.Connection.Fallback = builder.fallback
 }
 
+func (builder *ConnectionBuilder) addLogger() {
+
+	builder.Connection.Logger = builder.logger
+}
+
+func (builder *ConnectionBuilder) addRetryPolicy() {
+
+	builder.Connection.RetryMax = builder.retryMax
+	builder.Connection.RetryWaitMin = builder.retryWaitMin
+	builder.Connection.RetryWaitMax = builder.retryWaitMax
+	builder.Connection.CheckRetry = builder.checkRetry
+}
+
+func (builder *ConnectionBuilder) addTimeout() {
+
+	builder.Connection.Timeout = builder.timeout
+}
+
+func (builder *ConnectionBuilder) addHedge() {
+
+	builder.Connection.Hedge = builder.hedge
+}
+
+func (builder *ConnectionBuilder) addCircuitBreaker() {
+
+	builder.Connection.Breaker = builder.breaker
+}
+
+func (builder *ConnectionBuilder) addCodec() {
+
+	builder.Connection.Codec = builder.codec
+}
+
+func (builder *ConnectionBuilder) addClient() {
+
+	builder.Connection.Client = builder.client
+}
+
+func (builder *ConnectionBuilder) addMiddleware() {
+
+	builder.Connection.Middleware = builder.middleware
+}
+
 // ConnectionManager represents the Director structure that applies to
 // ConnectionBuilder when creating Connection instances.
 type ConnectionManager struct{}
@@ -109,8 +249,37 @@ func (manager *ConnectionManager) CreateConnection(builder *ConnectionBuilder) {
 
 	builder.addHTTPHeaders()
 	builder.addPayloads()
+	builder.addLogger()
 
 	if builder.fallback != nil {
 		builder.addFallback()
 	}
+
+	if builder.retryMax > 0 {
+		builder.addRetryPolicy()
+	}
+
+	if builder.timeout > 0 {
+		builder.addTimeout()
+	}
+
+	if builder.hedge > 0 {
+		builder.addHedge()
+	}
+
+	if builder.breaker != nil {
+		builder.addCircuitBreaker()
+	}
+
+	if builder.codec != nil {
+		builder.addCodec()
+	}
+
+	if builder.client != nil {
+		builder.addClient()
+	}
+
+	if len(builder.middleware) > 0 {
+		builder.addMiddleware()
+	}
 }
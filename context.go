@@ -0,0 +1,94 @@
+package fallback
+
+import (
+	"context"
+	"time"
+)
+
+// hedgeOutcome carries the result of a single racer (this Connection or its
+// Fallback) back to executeHedged.
+type hedgeOutcome struct {
+	statusCode int
+	err        error
+}
+
+// executeHedged races this Connection against Fallback once Hedge has
+// elapsed without a result, returning whichever succeeds first and canceling
+// the other via its context. It is only invoked when Hedge and Fallback are
+// both set; see the Connection.Hedge doc comment.
+func (connection Connection) executeHedged(ctx context.Context) (int, error) {
+
+	start := time.Now()
+
+	primaryCtx, cancelPrimary := context.WithCancel(ctx)
+	defer cancelPrimary()
+
+	primary := make(chan hedgeOutcome, 1)
+	go func() {
+		statusCode, err, _ := connection.executeInternal(primaryCtx)
+		primary <- hedgeOutcome{statusCode, err}
+	}()
+
+	timer := time.NewTimer(connection.Hedge)
+	defer timer.Stop()
+
+	select {
+	case r := <-primary:
+		if r.err == nil {
+			return r.statusCode, nil
+		}
+		fallbackStatusCode, fallbackErr := connection.Fallback.ExecuteHTTPRequestContext(ctx)
+		if fallbackErr == nil {
+			return fallbackStatusCode, nil
+		}
+		return fallbackStatusCode,
+			prependAttempt(connection.attemptResult(r, start), fallbackStatusCode, fallbackErr)
+	case <-timer.C:
+	}
+
+	fallbackCtx, cancelFallback := context.WithCancel(ctx)
+	defer cancelFallback()
+
+	fallback := make(chan hedgeOutcome, 1)
+	go func() {
+		statusCode, err := connection.Fallback.ExecuteHTTPRequestContext(fallbackCtx)
+		fallback <- hedgeOutcome{statusCode, err}
+	}()
+
+	select {
+	case r := <-primary:
+		if r.err == nil {
+			cancelFallback()
+			return r.statusCode, nil
+		}
+		fb := <-fallback
+		if fb.err == nil {
+			return fb.statusCode, nil
+		}
+		return fb.statusCode, prependAttempt(connection.attemptResult(r, start), fb.statusCode, fb.err)
+	case r := <-fallback:
+		if r.err == nil {
+			cancelPrimary()
+			return r.statusCode, nil
+		}
+		primaryResult := <-primary
+		if primaryResult.err == nil {
+			return primaryResult.statusCode, nil
+		}
+		return r.statusCode,
+			prependAttempt(connection.attemptResult(primaryResult, start), r.statusCode, r.err)
+	}
+}
+
+// attemptResult builds the AttemptResult describing this Connection's own
+// (non-fallback) racer in a hedged execution.
+func (connection Connection) attemptResult(r hedgeOutcome, start time.Time) AttemptResult {
+	return AttemptResult{
+		Name:       connection.Name,
+		Method:     connection.Method,
+		Path:       connection.Path,
+		StatusCode: r.statusCode,
+		Err:        r.err,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+}
@@ -0,0 +1,111 @@
+package fallback
+
+import (
+	"errors"
+	"strings"
+)
+
+// AttemptResult records the outcome of a single hop in the fallback chain,
+// whether that hop failed outright or was the final link that gave up.
+type AttemptResult struct {
+	Name       string
+	Method     string
+	Path       string
+	StatusCode int
+	Err        error
+	DurationMs int64
+}
+
+// FallbackError is returned by ExecuteHTTPRequestContext when every link in
+// the chain has been exhausted without success. It preserves the ordered
+// list of attempts, from the first Connection tried to the last, so callers
+// can inspect which hops failed and why rather than losing that context
+// behind a single generic string. Cause is the error of the last attempt.
+type FallbackError struct {
+	Attempts []AttemptResult
+	Cause    error
+}
+
+// Error implements the error interface, summarising every attempt in the
+// chain.
+func (fallbackErr *FallbackError) Error() string {
+
+	var b strings.Builder
+	b.WriteString("fallback: all attempts failed:")
+
+	for _, attempt := range fallbackErr.Attempts {
+		b.WriteString(" [")
+		b.WriteString(attempt.Name)
+		b.WriteString(": ")
+		if attempt.Err != nil {
+			b.WriteString(attempt.Err.Error())
+		} else {
+			b.WriteString("unknown error")
+		}
+		b.WriteString("]")
+	}
+
+	return b.String()
+}
+
+// Unwrap returns Cause, allowing errors.Is and errors.As to see through a
+// FallbackError to the terminal attempt's underlying error.
+func (fallbackErr *FallbackError) Unwrap() error {
+	return fallbackErr.Cause
+}
+
+// Is reports whether target matches the error of any attempt in the chain,
+// not just Cause, so callers can ask "did any hop fail with X" regardless of
+// where in the chain X occurred.
+func (fallbackErr *FallbackError) Is(target error) bool {
+
+	for _, attempt := range fallbackErr.Attempts {
+		if attempt.Err != nil && errors.Is(attempt.Err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first attempt error in the chain that matches target's type,
+// assigning it to target the same way errors.As would.
+func (fallbackErr *FallbackError) As(target interface{}) bool {
+
+	for _, attempt := range fallbackErr.Attempts {
+		if attempt.Err != nil && errors.As(attempt.Err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newAttemptError wraps err as the terminal FallbackError for a single
+// attempt, used when Fallback is nil or the failure is not fallback-eligible.
+func newAttemptError(attempt AttemptResult) *FallbackError {
+	return &FallbackError{
+		Attempts: []AttemptResult{attempt},
+		Cause:    attempt.Err,
+	}
+}
+
+// prependAttempt merges attempt onto the front of a fallback result's error,
+// producing a single FallbackError describing every hop in the chain. If err
+// is not already a *FallbackError (e.g. a custom Connecter implementation),
+// it is captured as a synthetic terminal attempt.
+func prependAttempt(attempt AttemptResult, statusCode int, err error) *FallbackError {
+
+	if fallbackErr, ok := err.(*FallbackError); ok {
+		attempts := make([]AttemptResult, 0, len(fallbackErr.Attempts)+1)
+		attempts = append(attempts, attempt)
+		attempts = append(attempts, fallbackErr.Attempts...)
+
+		return &FallbackError{Attempts: attempts, Cause: fallbackErr.Cause}
+	}
+
+	return &FallbackError{
+		Attempts: []AttemptResult{attempt, {StatusCode: statusCode, Err: err}},
+		Cause:    err,
+	}
+}
@@ -12,10 +12,12 @@ package fallback
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
 	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 )
 
 // Connecter represents the Handler abstraction in the Chain of Responsibility
@@ -24,6 +26,7 @@ import (
 // to take part in the chain.
 type Connecter interface {
 	ExecuteHTTPRequest() (int, error)
+	ExecuteHTTPRequestContext(ctx context.Context) (int, error)
 }
 
 // Logger represents an abstraction providing custom logging. Clients may apply
@@ -62,6 +65,43 @@ type Logger interface {
 //
 // Logger: Custom Logger implementations that publish events in the event of
 // any Fallback component failing to execute a HTTP request.
+//
+// RetryMax: The number of transient-failure retries attempted against this
+// Connection before falling back. Zero (the default) disables retries,
+// preserving prior behaviour.
+//
+// RetryWaitMin, RetryWaitMax: The bounds of the exponential backoff applied
+// between retries. Defaulted to DefaultRetryWaitMin/DefaultRetryWaitMax when
+// unset.
+//
+// CheckRetry: A hook that decides whether a given attempt should be retried.
+// Defaulted to DefaultCheckRetry when nil.
+//
+// Timeout: The maximum duration allowed for this Connection, applied via
+// context.WithTimeout around ExecuteHTTPRequestContext. Zero disables the
+// timeout, bounding execution only by the caller's context.
+//
+// Hedge: If greater than zero and Fallback is set, Fallback is raced against
+// this Connection once Hedge has elapsed without a result, and whichever
+// succeeds first wins, canceling the other. This mitigates tail latency
+// similarly to gRPC hedged calls.
+//
+// Breaker: An optional CircuitBreaker consulted before dialing this
+// Connection. When Breaker.Allow() reports false, the request is skipped
+// entirely and the process flow shifts directly to Fallback.
+//
+// Codec: An optional override used to marshal Body and unmarshal Output and
+// CustomError. When nil, the Codec registered for the HTTP response's
+// Content-Type is used, falling back to JSON.
+//
+// Client: An optional *http.Client used to dial this Connection, allowing
+// custom TLS configuration, proxies, mTLS, or interception in tests. When
+// nil, DefaultClient is used, so keep-alive pools are still shared across
+// fallback hops.
+//
+// Middleware: An ordered chain of Middleware wrapping the underlying round
+// trip, for injecting auth, request signing, tracing, or metrics. The first
+// entry is outermost.
 type Connection struct {
 	Name, Method, Path  string
 	Body                []byte
@@ -69,6 +109,21 @@ type Connection struct {
 	Output, CustomError interface{}
 	Fallback            Connecter
 	Logger              Logger
+
+	RetryMax                   int
+	RetryWaitMin, RetryWaitMax time.Duration
+	CheckRetry                 CheckRetryFunc
+
+	Timeout time.Duration
+	Hedge   time.Duration
+
+	Breaker CircuitBreaker
+
+	Codec Codec
+
+	Client *http.Client
+
+	Middleware []Middleware
 }
 
 // NewConnection returns a new Connection instance based on the specified
@@ -78,31 +133,32 @@ func NewConnection(name, method, path string, body []byte,
 	fallback Connecter, logger Logger) *Connection {
 
 	return &Connection{
-		name,
-		method,
-		path,
-		body,
-		headers,
-		output,
-		customError,
-		fallback,
-		logger,
+		Name:        name,
+		Method:      method,
+		Path:        path,
+		Body:        body,
+		Headers:     headers,
+		Output:      output,
+		CustomError: customError,
+		Fallback:    fallback,
+		Logger:      logger,
 	}
 }
 
-// CreateHTTPRequest instantiates a http.Request based on connection metadata.
-// The method returns a pointer to the constructed http.Request, or an error,
-// if the URL is invalid.
-func (connection Connection) createHTTPRequest() (*http.Request, error) {
+// CreateHTTPRequest instantiates a http.Request based on connection metadata,
+// bound to ctx via http.NewRequestWithContext. The method returns a pointer to
+// the constructed http.Request, or an error, if the URL is invalid.
+func (connection Connection) createHTTPRequest(ctx context.Context) (*http.Request, error) {
 
 	var request *http.Request
 	var err error
 
 	if connection.Body == nil {
-		request, err = http.NewRequest(connection.Method, connection.Path, nil)
+		request, err = http.NewRequestWithContext(ctx, connection.Method,
+			connection.Path, nil)
 	} else {
-		request, err = http.NewRequest(connection.Method, connection.Path,
-			bytes.NewBuffer(connection.Body))
+		request, err = http.NewRequestWithContext(ctx, connection.Method,
+			connection.Path, bytes.NewBuffer(connection.Body))
 	}
 
 	if err != nil {
@@ -123,6 +179,12 @@ func (connection Connection) createHTTPRequest() (*http.Request, error) {
 // connection. Should this fail, the process flow shifts to any fallback method
 // applied to Connection. If no fallback method is specified, the method returns.
 //
+// If RetryMax is greater than zero, transient failures (connection errors,
+// HTTP 429, and HTTP 5xx responses, or as otherwise determined by CheckRetry)
+// are retried against this Connection, using exponential backoff with full
+// jitter between RetryWaitMin and RetryWaitMax, before the process flow shifts
+// to Fallback.
+//
 // Unreachable URIs will yield a HTTP 503 response. Invalid URIs will yield a
 // HTTP 400 response. Neither response will yield a response body.
 //
@@ -143,38 +205,106 @@ func (connection Connection) createHTTPRequest() (*http.Request, error) {
 // is established, or all attempts fail. If the HTTP Response Body is not set,
 // or cannot be deserialised to Connection.CustomError, an error is returned
 // along with the HTTP status code.
+//
+// ExecuteHTTPRequest is equivalent to ExecuteHTTPRequestContext with
+// context.Background().
 func (connection Connection) ExecuteHTTPRequest() (int, error) {
 
-	client := &http.Client{}
+	return connection.ExecuteHTTPRequestContext(context.Background())
+}
+
+// ExecuteHTTPRequestContext behaves identically to ExecuteHTTPRequest, except
+// that ctx is threaded through the underlying http.Request and every
+// recursive Fallback call, so callers can cancel the entire chain, or bound it
+// with a deadline, from outside. If Timeout is set, it is additionally applied
+// to this Connection via context.WithTimeout. If Hedge is set and Fallback is
+// present, Fallback races this Connection once Hedge elapses without a
+// result; see the Connection.Hedge doc comment.
+func (connection Connection) ExecuteHTTPRequestContext(ctx context.Context) (int, error) {
+
+	if connection.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, connection.Timeout)
+		defer cancel()
+	}
 
-	request, err := connection.createHTTPRequest()
+	if connection.Hedge > 0 && connection.Fallback != nil {
+		return connection.executeHedged(ctx)
+	}
+
+	start := time.Now()
+	statusCode, err, fallbackEligible := connection.executeInternal(ctx)
+
+	if err == nil || !fallbackEligible {
+		return statusCode, err
+	}
+
+	thisAttempt := AttemptResult{
+		Name:       connection.Name,
+		Method:     connection.Method,
+		Path:       connection.Path,
+		StatusCode: statusCode,
+		Err:        err,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+
+	if connection.Fallback == nil {
+		return statusCode, newAttemptError(thisAttempt)
+	}
+
+	fallbackStatusCode, fallbackErr := connection.Fallback.ExecuteHTTPRequestContext(ctx)
+	if fallbackErr == nil {
+		return fallbackStatusCode, nil
+	}
+
+	return fallbackStatusCode, prependAttempt(thisAttempt, fallbackStatusCode, fallbackErr)
+}
+
+// executeInternal performs a single attempt (including any configured
+// retries) against this Connection, without itself recursing into Fallback.
+// The returned bool reports whether a non-nil error is eligible for fallback,
+// matching ExecuteHTTPRequest's historical behaviour: connection failures and
+// non-2xx responses are eligible, but a failure to deserialise a 2xx response
+// body into Connection.Output is not. Any terminal (non-fallback-eligible)
+// error is returned as a *FallbackError describing this single attempt.
+func (connection Connection) executeInternal(ctx context.Context) (int, error, bool) {
+
+	name, method, path := connection.Name, connection.Method, connection.Path
+
+	if connection.Breaker != nil && !connection.Breaker.Allow() {
+		if connection.Logger != nil {
+			connection.Logger.Log(connection.Name + " circuit breaker open, skipping request")
+		}
+		return 503, errors.New(connection.Name + ": circuit breaker open"), true
+	}
+
+	client := connection.httpClient()
+
+	start := time.Now()
+
+	request, err := connection.createHTTPRequest(ctx)
 	if err != nil {
 		if connection.Logger != nil {
 			connection.Logger.Log(connection.Name + " failed: " + err.Error())
 		}
+		return 400, err, true
+	}
 
-		if connection.Fallback != nil {
-			statusCode, err :=
-				connection.Fallback.ExecuteHTTPRequest()
+	resp, err := connection.doWithRetry(ctx, client, request)
 
-			return statusCode, err
+	if connection.Breaker != nil {
+		if err != nil || (resp != nil && resp.StatusCode >= 500) {
+			connection.Breaker.Failure()
+		} else {
+			connection.Breaker.Success()
 		}
-		return 400, err
 	}
 
-	resp, err := client.Do(request)
 	if err != nil {
 		if connection.Logger != nil {
 			connection.Logger.Log(connection.Name + " failed: " + err.Error())
 		}
-
-		if connection.Fallback != nil {
-			statusCode, err :=
-				connection.Fallback.ExecuteHTTPRequest()
-
-			return statusCode, err
-		}
-		return 503, err
+		return 503, err, true
 	}
 	defer resp.Body.Close()
 
@@ -186,27 +316,52 @@ func (connection Connection) ExecuteHTTPRequest() (int, error) {
 		}
 
 		if connection.Fallback != nil {
-			statusCode, err :=
-				connection.Fallback.ExecuteHTTPRequest()
+			return resp.StatusCode,
+				errors.New(connection.Name + " returned HTTP Error"), true
+		}
 
-			return statusCode, err
+		body, readErr := io.ReadAll(resp.Body)
+		if readErr != nil {
+			return resp.StatusCode, newAttemptError(AttemptResult{
+				Name: name, Method: method, Path: path,
+				StatusCode: resp.StatusCode,
+				Err:        errors.New("Unable to parse HTTP Response body."),
+				DurationMs: time.Since(start).Milliseconds(),
+			}), false
 		}
 
-		dec := json.NewDecoder(resp.Body)
-		err := dec.Decode(connection.CustomError)
-		if err != nil {
-			return resp.StatusCode,
-				errors.New("Unable to parse HTTP Response body.")
+		codec := connection.resolveCodec(resp.Header.Get("Content-Type"))
+		if err := codec.Unmarshal(body, connection.CustomError); err != nil {
+			return resp.StatusCode, newAttemptError(AttemptResult{
+				Name: name, Method: method, Path: path,
+				StatusCode: resp.StatusCode,
+				Err:        errors.New("Unable to parse HTTP Response body."),
+				DurationMs: time.Since(start).Milliseconds(),
+			}), false
 		}
 
-		return resp.StatusCode, nil
+		return resp.StatusCode, nil, false
 	}
 
-	dec := json.NewDecoder(resp.Body)
-	err = dec.Decode(connection.Output)
+	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return resp.StatusCode, errors.New("Unable to parse HTTP Response body.")
+		return resp.StatusCode, newAttemptError(AttemptResult{
+			Name: name, Method: method, Path: path,
+			StatusCode: resp.StatusCode,
+			Err:        errors.New("Unable to parse HTTP Response body."),
+			DurationMs: time.Since(start).Milliseconds(),
+		}), false
+	}
+
+	codec := connection.resolveCodec(resp.Header.Get("Content-Type"))
+	if err := codec.Unmarshal(body, connection.Output); err != nil {
+		return resp.StatusCode, newAttemptError(AttemptResult{
+			Name: name, Method: method, Path: path,
+			StatusCode: resp.StatusCode,
+			Err:        errors.New("Unable to parse HTTP Response body."),
+			DurationMs: time.Since(start).Milliseconds(),
+		}), false
 	}
 
-	return resp.StatusCode, nil
+	return resp.StatusCode, nil, false
 }
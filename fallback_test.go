@@ -1,6 +1,15 @@
 package fallback
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
 
 type BasicResponse struct {
 	Text   string
@@ -130,8 +139,8 @@ func TestFallbackBuilder(t *testing.T) {
 	basicResponse := BasicResponse{}
 	basicError := BasicError{}
 
-	builder := NewConnectionBuilder("CONN1", "GET", path, true, nil,
-		&basicResponse, &basicError, nil, nil, nil)
+	builder := NewConnectionBuilder("CONN1", "GET", path, true, nil, nil,
+		&basicResponse, &basicError, nil, nil)
 
 	connectionManager := ConnectionManager{}
 	connectionManager.CreateConnection(builder)
@@ -167,16 +176,16 @@ func TestComplexFallbackBuilder(t *testing.T) {
 
 	connectionManager := ConnectionManager{}
 
-	passBuilder := NewConnectionBuilder("PASS", "GET", passPath, true, nil,
-		&basicResponse, &basicError, nil, nil, nil)
+	passBuilder := NewConnectionBuilder("PASS", "GET", passPath, true, nil, nil,
+		&basicResponse, &basicError, nil, nil)
 	connectionManager.CreateConnection(passBuilder)
 
-	failBuilder2 := NewConnectionBuilder("FAIL2", "POST", failPath2, true, nil,
-		&basicResponse, &basicError, nil, passBuilder.Connection, nil)
+	failBuilder2 := NewConnectionBuilder("FAIL2", "POST", failPath2, true, nil, nil,
+		&basicResponse, &basicError, passBuilder.Connection, nil)
 	connectionManager.CreateConnection(failBuilder2)
 
-	failBuilder1 := NewConnectionBuilder("FAIL1", "POST", failPath1, true, nil,
-		&basicResponse, &basicError, nil, failBuilder2.Connection, nil)
+	failBuilder1 := NewConnectionBuilder("FAIL1", "POST", failPath1, true, nil, nil,
+		&basicResponse, &basicError, failBuilder2.Connection, nil)
 	connectionManager.CreateConnection(failBuilder1)
 
 	statusCode, err := failBuilder1.Connection.ExecuteHTTPRequest()
@@ -198,3 +207,340 @@ func TestComplexFallbackBuilder(t *testing.T) {
 			"got", basicResponse.Text, basicResponse.Detail)
 	}
 }
+
+func TestRetryRecoversAfterTransientFailures(t *testing.T) {
+
+	var attempts int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Text":"OK","Detail":"Successful HTTP request"}`))
+	}))
+	defer server.Close()
+
+	basicResponse := &BasicResponse{}
+	basicError := &BasicError{}
+
+	builder := NewConnectionBuilder("RETRY", "GET", server.URL, true, nil, nil,
+		basicResponse, basicError, nil, nil)
+	builder.WithRetry(3, time.Millisecond, 5*time.Millisecond, nil)
+
+	connectionManager := ConnectionManager{}
+	connectionManager.CreateConnection(builder)
+
+	statusCode, err := builder.Connection.ExecuteHTTPRequest()
+
+	if err != nil {
+		t.Fatal("Status Code:", statusCode, "Error:", err)
+	}
+
+	if statusCode != 200 {
+		t.Fatal("For", "Retry recovery",
+			"expected", 200,
+			"got", statusCode)
+	}
+
+	if atomic.LoadInt32(&attempts) != 3 {
+		t.Error("For", "Retry recovery",
+			"expected", 3, "attempts",
+			"got", attempts)
+	}
+
+	if basicResponse.Text != "OK" {
+		t.Error("For", "Retry recovery",
+			"expected", "OK",
+			"got", basicResponse.Text)
+	}
+}
+
+func TestHedgeRacesFallback(t *testing.T) {
+
+	primaryServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Text":"PRIMARY","Detail":"slow"}`))
+	}))
+	defer primaryServer.Close()
+
+	fallbackServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Text":"FALLBACK","Detail":"fast"}`))
+	}))
+	defer fallbackServer.Close()
+
+	basicResponse := &BasicResponse{}
+	basicError := &BasicError{}
+
+	fallback := NewConnection("Fallback", "GET", fallbackServer.URL, nil, nil,
+		basicResponse, basicError, nil, nil)
+
+	primary := NewConnection("Primary", "GET", primaryServer.URL, nil, nil,
+		basicResponse, basicError, fallback, nil)
+	primary.Hedge = 10 * time.Millisecond
+
+	statusCode, err := primary.ExecuteHTTPRequest()
+
+	if err != nil {
+		t.Fatal("Status Code:", statusCode, "Error:", err)
+	}
+
+	if statusCode != 200 {
+		t.Fatal("For", "Hedge races fallback",
+			"expected", 200,
+			"got", statusCode)
+	}
+
+	if basicResponse.Text != "FALLBACK" {
+		t.Error("For", "Hedge races fallback",
+			"expected", "FALLBACK",
+			"got", basicResponse.Text)
+	}
+}
+
+func TestCircuitBreakerTripsThenHalfOpens(t *testing.T) {
+
+	var serverHits int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&serverHits, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	basicResponse := &BasicResponse{}
+	basicError := &BasicError{}
+
+	conn := NewConnection("BREAKER", "GET", server.URL, nil, nil,
+		basicResponse, basicError, nil, nil)
+	conn.Breaker = NewDefaultCircuitBreaker(2, 20*time.Millisecond, 1)
+
+	conn.ExecuteHTTPRequest()
+	conn.ExecuteHTTPRequest()
+
+	if hits := atomic.LoadInt32(&serverHits); hits != 2 {
+		t.Fatal("For", "Circuit breaker warm-up",
+			"expected", 2, "hits",
+			"got", hits)
+	}
+
+	statusCode, err := conn.ExecuteHTTPRequest()
+
+	if err == nil || statusCode != 503 {
+		t.Fatal("For", "Circuit breaker open",
+			"expected", 503,
+			"got", statusCode)
+	}
+
+	if hits := atomic.LoadInt32(&serverHits); hits != 2 {
+		t.Error("For", "Circuit breaker open",
+			"expected", "request to be skipped without hitting the server",
+			"got", hits, "hits")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	conn.ExecuteHTTPRequest()
+
+	if hits := atomic.LoadInt32(&serverHits); hits != 3 {
+		t.Error("For", "Circuit breaker half-open probe",
+			"expected", 3, "hits",
+			"got", hits)
+	}
+}
+
+func TestFallbackErrorAttemptsAndIs(t *testing.T) {
+
+	terminalServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer terminalServer.Close()
+
+	firstServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer firstServer.Close()
+
+	basicResponse := &BasicResponse{}
+	basicError := &BasicError{}
+
+	terminal := NewConnection("TERMINAL", "GET", terminalServer.URL, nil, nil,
+		basicResponse, basicError, nil, nil)
+
+	first := NewConnection("FIRST", "GET", firstServer.URL, nil, nil,
+		basicResponse, basicError, terminal, nil)
+
+	_, err := first.ExecuteHTTPRequest()
+
+	var fallbackErr *FallbackError
+	if !errors.As(err, &fallbackErr) {
+		t.Fatal("For", "FallbackError",
+			"expected", "*FallbackError",
+			"got", err)
+	}
+
+	if len(fallbackErr.Attempts) != 2 {
+		t.Fatal("For", "FallbackError.Attempts",
+			"expected", 2, "attempts",
+			"got", len(fallbackErr.Attempts))
+	}
+
+	if fallbackErr.Attempts[0].Name != "FIRST" || fallbackErr.Attempts[1].Name != "TERMINAL" {
+		t.Error("For", "FallbackError.Attempts order",
+			"expected", "[FIRST TERMINAL]",
+			"got", fallbackErr.Attempts[0].Name, fallbackErr.Attempts[1].Name)
+	}
+
+	if fallbackErr.Is(errors.New("sentinel")) {
+		t.Error("For", "FallbackError.Is with an unrelated sentinel",
+			"expected", false,
+			"got", true)
+	}
+}
+
+func TestCodecSelectedByResponseContentType(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(`<BasicResponse><Text>OK</Text><Detail>Successful HTTP request</Detail></BasicResponse>`))
+	}))
+	defer server.Close()
+
+	basicResponse := &BasicResponse{}
+	basicError := &BasicError{}
+
+	conn := NewConnection("XML", "GET", server.URL, nil, nil,
+		basicResponse, basicError, nil, nil)
+
+	statusCode, err := conn.ExecuteHTTPRequest()
+
+	if err != nil {
+		t.Fatal("Status Code:", statusCode, "Error:", err)
+	}
+
+	if basicResponse.Text != "OK" || basicResponse.Detail != "Successful HTTP request" {
+		t.Error("For", "XML response",
+			"expected", "OK, Successful HTTP request",
+			"got", basicResponse.Text, basicResponse.Detail)
+	}
+}
+
+func TestConnectionBuilderContentTypeMatchesCodec(t *testing.T) {
+
+	builder := NewConnectionBuilder("XML-BUILD", "POST", "http://example.invalid", true,
+		BasicResponse{Text: "OK"}, nil, &BasicResponse{}, &BasicError{}, nil, nil)
+	builder.WithCodec(xmlCodec{})
+
+	connectionManager := ConnectionManager{}
+	connectionManager.CreateConnection(builder)
+
+	if got := builder.Connection.Headers["Content-Type"]; got != "application/xml" {
+		t.Error("For", "Content-Type derived from codec",
+			"expected", "application/xml",
+			"got", got)
+	}
+}
+
+func TestWithClientUsesInjectedClient(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Text":"OK","Detail":"Successful HTTP request"}`))
+	}))
+	defer server.Close()
+
+	client := server.Client()
+
+	basicResponse := &BasicResponse{}
+	basicError := &BasicError{}
+
+	builder := NewConnectionBuilder("CLIENT", "GET", server.URL, true, nil, nil,
+		basicResponse, basicError, nil, nil)
+	builder.WithClient(client)
+
+	connectionManager := ConnectionManager{}
+	connectionManager.CreateConnection(builder)
+
+	if builder.Connection.Client != client {
+		t.Fatal("For", "Injected client",
+			"expected", "builder.Connection.Client to be the injected client",
+			"got", "a different client")
+	}
+
+	statusCode, err := builder.Connection.ExecuteHTTPRequest()
+
+	if err != nil {
+		t.Fatal("Status Code:", statusCode, "Error:", err)
+	}
+
+	if statusCode != 200 {
+		t.Fatal("For", "Injected client",
+			"expected", 200,
+			"got", statusCode)
+	}
+}
+
+func TestAuthMiddlewareRefreshesTokenOn401(t *testing.T) {
+
+	var tokenCalls int32
+	var tokensMu sync.Mutex
+	var sawTokens []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+
+		tokensMu.Lock()
+		sawTokens = append(sawTokens, auth)
+		tokensMu.Unlock()
+
+		if auth == "Bearer stale" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Text":"OK","Detail":"Successful HTTP request"}`))
+	}))
+	defer server.Close()
+
+	tokenSource := func(ctx context.Context) (string, error) {
+		if atomic.AddInt32(&tokenCalls, 1) == 1 {
+			return "stale", nil
+		}
+		return "fresh", nil
+	}
+
+	basicResponse := &BasicResponse{}
+	basicError := &BasicError{}
+
+	builder := NewConnectionBuilder("AUTH", "GET", server.URL, true, nil, nil,
+		basicResponse, basicError, nil, nil)
+	builder.WithMiddleware(AuthMiddleware(tokenSource))
+
+	connectionManager := ConnectionManager{}
+	connectionManager.CreateConnection(builder)
+
+	statusCode, err := builder.Connection.ExecuteHTTPRequest()
+
+	if err != nil {
+		t.Fatal("Status Code:", statusCode, "Error:", err)
+	}
+
+	if statusCode != 200 {
+		t.Fatal("For", "Auth retry on 401",
+			"expected", 200,
+			"got", statusCode)
+	}
+
+	tokensMu.Lock()
+	defer tokensMu.Unlock()
+
+	if len(sawTokens) != 2 || sawTokens[0] != "Bearer stale" || sawTokens[1] != "Bearer fresh" {
+		t.Error("For", "Auth retry on 401",
+			"expected", "[Bearer stale Bearer fresh]",
+			"got", sawTokens)
+	}
+}
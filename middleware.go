@@ -0,0 +1,119 @@
+package fallback
+
+import (
+	"context"
+	"net/http"
+)
+
+// RoundTrip performs a single HTTP round trip for request under ctx. It is
+// the unit composed by Middleware, terminating in a call to the Connection's
+// *http.Client.
+type RoundTrip func(ctx context.Context, request *http.Request) (*http.Response, error)
+
+// Middleware wraps a RoundTrip with additional behaviour - authentication,
+// request signing, tracing, metrics - without the caller needing to fork this
+// package. Middlewares on a Connection are applied in the order they appear
+// in Middleware, so the first entry is outermost.
+type Middleware func(next RoundTrip) RoundTrip
+
+// send executes request against client, passing it through any configured
+// Middleware in order before the terminal round trip.
+func (connection Connection) send(ctx context.Context, client *http.Client,
+	request *http.Request) (*http.Response, error) {
+
+	var rt RoundTrip = func(ctx context.Context, request *http.Request) (*http.Response, error) {
+		return client.Do(request.WithContext(ctx))
+	}
+
+	for i := len(connection.Middleware) - 1; i >= 0; i-- {
+		rt = connection.Middleware[i](rt)
+	}
+
+	return rt(ctx, request)
+}
+
+// TokenSource supplies the bearer token used by AuthMiddleware, invoked once
+// per request and again after a 401 to obtain a refreshed token.
+type TokenSource func(ctx context.Context) (string, error)
+
+// AuthMiddleware returns a Middleware that sets an Authorization: Bearer
+// header from tokenSource before every request, and, if the underlying round
+// trip returns HTTP 401, refreshes the token via tokenSource and retries the
+// request once before giving up (and allowing ExecuteHTTPRequestContext to
+// fall back as usual).
+func AuthMiddleware(tokenSource TokenSource) Middleware {
+
+	return func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, request *http.Request) (*http.Response, error) {
+
+			token, err := tokenSource(ctx)
+			if err != nil {
+				return nil, err
+			}
+			request.Header.Set("Authorization", "Bearer "+token)
+
+			resp, err := next(ctx, request)
+			if err != nil || resp.StatusCode != http.StatusUnauthorized {
+				return resp, err
+			}
+			resp.Body.Close()
+
+			token, err = tokenSource(ctx)
+			if err != nil {
+				return resp, err
+			}
+
+			retryRequest := request.Clone(ctx)
+			retryRequest.Header.Set("Authorization", "Bearer "+token)
+
+			if request.GetBody != nil {
+				body, bodyErr := request.GetBody()
+				if bodyErr != nil {
+					return resp, bodyErr
+				}
+				retryRequest.Body = body
+			}
+
+			return next(ctx, retryRequest)
+		}
+	}
+}
+
+// Span represents a single traced operation, shaped after
+// go.opentelemetry.io/otel/trace.Span so an OpenTelemetry tracer can be
+// adapted to it with a thin wrapper.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// Tracer starts a Span describing the operation named name, returning a
+// derived context that carries it. Shaped after
+// go.opentelemetry.io/otel/trace.Tracer for the same reason as Span.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// TracingMiddleware returns a Middleware that starts a Span around each hop
+// in the fallback chain via tracer, named after the Connection's Method and
+// Path, so a distributed trace shows every attempt.
+func TracingMiddleware(tracer Tracer) Middleware {
+
+	return func(next RoundTrip) RoundTrip {
+		return func(ctx context.Context, request *http.Request) (*http.Response, error) {
+
+			spanCtx, span := tracer.Start(ctx, request.Method+" "+request.URL.Path)
+			defer span.End()
+
+			resp, err := next(spanCtx, request)
+			if err != nil {
+				span.RecordError(err)
+				return resp, err
+			}
+
+			span.SetAttribute("http.status_code", resp.StatusCode)
+			return resp, nil
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package fallback
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultRetryWaitMin and DefaultRetryWaitMax bound the exponential backoff
+// applied between retry attempts when a Connection enables retries (RetryMax
+// greater than zero) without specifying its own RetryWaitMin/RetryWaitMax.
+const (
+	DefaultRetryWaitMin = 1 * time.Second
+	DefaultRetryWaitMax = 30 * time.Second
+)
+
+// CheckRetryFunc inspects the outcome of a single HTTP attempt and decides
+// whether Connection should retry before falling back. Implementations
+// returning a non-nil error abort the retry loop immediately, treating that
+// error as the attempt's terminal failure.
+type CheckRetryFunc func(resp *http.Response, err error) (bool, error)
+
+// DefaultCheckRetry is the CheckRetryFunc applied when Connection.CheckRetry
+// is nil. It retries on connection errors and on HTTP 429 and 5xx responses,
+// the same policy used by go-retryablehttp.
+func DefaultCheckRetry(resp *http.Response, err error) (bool, error) {
+
+	if err != nil {
+		return true, nil
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// doWithRetry performs the HTTP round trip for request, retrying transient
+// failures with exponential backoff and full jitter (sleep = random(0,
+// min(RetryWaitMax, RetryWaitMin * 2^attempt))) before allowing
+// ExecuteHTTPRequest to fall through to Fallback. A Connection with RetryMax
+// of zero performs a single attempt, preserving prior behaviour. The backoff
+// sleep honours ctx cancellation so a caller's deadline is never overrun.
+func (connection Connection) doWithRetry(ctx context.Context, client *http.Client,
+	request *http.Request) (*http.Response, error) {
+
+	checkRetry := connection.CheckRetry
+	if checkRetry == nil {
+		checkRetry = DefaultCheckRetry
+	}
+
+	var resp *http.Response
+	var err error
+
+	for attempt := 0; ; attempt++ {
+
+		if attempt > 0 && request.GetBody != nil {
+			body, bodyErr := request.GetBody()
+			if bodyErr != nil {
+				return resp, bodyErr
+			}
+			request.Body = body
+		}
+
+		resp, err = connection.send(ctx, client, request)
+
+		shouldRetry, retryErr := checkRetry(resp, err)
+		if retryErr != nil {
+			return resp, retryErr
+		}
+
+		if !shouldRetry || attempt >= connection.RetryMax {
+			return resp, err
+		}
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(connection.retryDelay(resp, attempt))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return resp, ctx.Err()
+		}
+	}
+}
+
+// retryDelay computes the backoff before the next retry attempt, honouring a
+// Retry-After response header when present and otherwise applying
+// exponential backoff with full jitter between RetryWaitMin and RetryWaitMax.
+func (connection Connection) retryDelay(resp *http.Response, attempt int) time.Duration {
+
+	if resp != nil {
+		if retryAfter := parseRetryAfter(resp.Header.Get("Retry-After")); retryAfter > 0 {
+			return retryAfter
+		}
+	}
+
+	waitMin := connection.RetryWaitMin
+	if waitMin <= 0 {
+		waitMin = DefaultRetryWaitMin
+	}
+
+	waitMax := connection.RetryWaitMax
+	if waitMax <= 0 {
+		waitMax = DefaultRetryWaitMax
+	}
+
+	ceiling := waitMin << uint(attempt)
+	if ceiling <= 0 || ceiling > waitMax {
+		ceiling = waitMax
+	}
+
+	return time.Duration(rand.Int63n(int64(ceiling)))
+}
+
+// parseRetryAfter interprets a Retry-After header value expressed either as a
+// number of seconds or a HTTP date, returning zero if header is empty or
+// unparseable.
+func parseRetryAfter(header string) time.Duration {
+
+	if header == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if when, err := http.ParseTime(header); err == nil {
+		return time.Until(when)
+	}
+
+	return 0
+}